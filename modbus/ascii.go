@@ -0,0 +1,101 @@
+package modbus
+
+import (
+	"bufio"
+	"encoding/hex"
+	"fmt"
+	"io"
+	"time"
+)
+
+const (
+	// asciiMinSize is the shortest possible ASCII frame: ':' + slave ID
+	// (2 hex chars) + function code (2 hex chars) + LRC (2 hex chars) +
+	// "\r\n".
+	asciiMinSize = 1 + 2 + 2 + 2 + 2
+	// asciiMaxSize bounds a single ASCII frame (252 byte PDU, per the
+	// Modbus spec, hex-encoded, plus framing).
+	asciiMaxSize = 1 + 2*(1+252) + 2 + 2
+)
+
+// encodeASCII builds a ':'-prefixed hex ASCII frame terminated by
+// "\r\n": slave ID, PDU, and an LRC checksum over both.
+func encodeASCII(slaveID byte, pdu []byte) []byte {
+	body := make([]byte, 0, 1+len(pdu)+1)
+	body = append(body, slaveID)
+	body = append(body, pdu...)
+	body = append(body, lrc(body))
+
+	frame := make([]byte, 0, asciiMaxSize)
+	frame = append(frame, ':')
+	frame = append(frame, []byte(toHexUpper(body))...)
+	frame = append(frame, '\r', '\n')
+	return frame
+}
+
+// readASCII reads one ':'-delimited ASCII frame terminated by "\r\n" from
+// r, decodes the hex body, and validates its LRC. Unlike RTU, ASCII
+// framing carries no inter-character timing information, so the whole
+// frame (start delimiter through "\r\n") is read under a single
+// responseTimeout deadline rather than the inter-frame silence.
+func readASCII(r io.Reader, responseTimeout time.Duration) (slaveID byte, pdu []byte, err error) {
+	br := bufio.NewReader(&deadlineReader{r: r, deadline: time.Now().Add(responseTimeout)})
+
+	c, err := br.ReadByte()
+	if err != nil {
+		return 0, nil, fmt.Errorf("modbus: read: %w", err)
+	}
+	if c != ':' {
+		return 0, nil, fmt.Errorf("modbus: ASCII frame missing ':' start delimiter")
+	}
+
+	line, err := br.ReadString('\n')
+	if err != nil {
+		return 0, nil, fmt.Errorf("modbus: read: %w", err)
+	}
+	if len(line) < 2 || line[len(line)-2] != '\r' {
+		return 0, nil, fmt.Errorf("modbus: ASCII frame missing \\r\\n terminator")
+	}
+	hexBody := line[:len(line)-2]
+
+	frameSize := 1 + len(hexBody) + 2
+	if frameSize < asciiMinSize || frameSize > asciiMaxSize {
+		return 0, nil, fmt.Errorf("modbus: ASCII frame size %d outside [%d, %d]", frameSize, asciiMinSize, asciiMaxSize)
+	}
+
+	body, err := hex.DecodeString(hexBody)
+	if err != nil {
+		return 0, nil, fmt.Errorf("modbus: ASCII frame is not valid hex: %w", err)
+	}
+	if len(body) < 3 {
+		return 0, nil, fmt.Errorf("modbus: ASCII frame too short (%d bytes)", len(body))
+	}
+
+	want := lrc(body[:len(body)-1])
+	got := body[len(body)-1]
+	if want != got {
+		return 0, nil, fmt.Errorf("modbus: ASCII LRC mismatch (got %#02x, want %#02x)", got, want)
+	}
+
+	return body[0], body[1 : len(body)-1], nil
+}
+
+// lrc computes the Modbus longitudinal redundancy check over data: the
+// two's complement of the sum of all bytes.
+func lrc(data []byte) byte {
+	var sum byte
+	for _, b := range data {
+		sum += b
+	}
+	return byte(-sum)
+}
+
+func toHexUpper(data []byte) string {
+	const digits = "0123456789ABCDEF"
+	out := make([]byte, len(data)*2)
+	for i, b := range data {
+		out[i*2] = digits[b>>4]
+		out[i*2+1] = digits[b&0x0F]
+	}
+	return string(out)
+}
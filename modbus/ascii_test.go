@@ -0,0 +1,72 @@
+package modbus
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestLRCKnownVector(t *testing.T) {
+	// Same request as the CRC-16 vector: slave 1, function 3, starting
+	// address 0, quantity 10.
+	got := lrc([]byte{0x01, 0x03, 0x00, 0x00, 0x00, 0x0A})
+	want := byte(0xF2)
+	if got != want {
+		t.Fatalf("lrc() = %#02x, want %#02x", got, want)
+	}
+}
+
+func TestEncodeASCIIRoundTrip(t *testing.T) {
+	frame := encodeASCII(0x11, []byte{0x03, 0x02, 0x00, 0x2A})
+	if !strings.HasPrefix(string(frame), ":") || !strings.HasSuffix(string(frame), "\r\n") {
+		t.Fatalf("encodeASCII() = %q, want ':'-prefixed \\r\\n-terminated frame", frame)
+	}
+
+	slaveID, pdu, err := readASCII(bytes.NewReader(frame), time.Second)
+	if err != nil {
+		t.Fatalf("readASCII() unexpected error: %v", err)
+	}
+	if slaveID != 0x11 {
+		t.Fatalf("readASCII() slaveID = %#x, want 0x11", slaveID)
+	}
+	want := []byte{0x03, 0x02, 0x00, 0x2A}
+	if !bytes.Equal(pdu, want) {
+		t.Fatalf("readASCII() pdu = % x, want % x", pdu, want)
+	}
+}
+
+func TestReadASCIIMissingColon(t *testing.T) {
+	_, _, err := readASCII(bytes.NewReader([]byte("1103020000\r\n")), time.Second)
+	if err == nil {
+		t.Fatal("readASCII() expected an error for a missing ':' delimiter, got nil")
+	}
+}
+
+func TestReadASCIILRCMismatch(t *testing.T) {
+	frame := encodeASCII(0x11, []byte{0x03, 0x02, 0x00, 0x2A})
+	// Flip a bit in the hex-encoded body, ahead of the LRC byte, without
+	// touching the ':' or "\r\n" framing.
+	frame[3] ^= 0x01
+	_, _, err := readASCII(bytes.NewReader(frame), time.Second)
+	if err == nil {
+		t.Fatal("readASCII() expected an LRC mismatch error, got nil")
+	}
+}
+
+func TestReadASCIIFrameTooShort(t *testing.T) {
+	// A well-formed but too-short body: fewer hex chars than
+	// asciiMinSize allows for.
+	_, _, err := readASCII(bytes.NewReader([]byte(":1103\r\n")), time.Second)
+	if err == nil {
+		t.Fatal("readASCII() expected an error for an undersized frame, got nil")
+	}
+}
+
+func TestReadASCIIFrameTooLong(t *testing.T) {
+	hexBody := strings.Repeat("00", 300) // well past asciiMaxSize
+	_, _, err := readASCII(bytes.NewReader([]byte(":"+hexBody+"\r\n")), time.Second)
+	if err == nil {
+		t.Fatal("readASCII() expected an error for an oversized frame, got nil")
+	}
+}
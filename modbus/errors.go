@@ -0,0 +1,58 @@
+package modbus
+
+import "fmt"
+
+// ExceptionCode is the single-byte exception code returned by a slave in
+// place of the requested data, per the Modbus Application Protocol spec.
+type ExceptionCode byte
+
+const (
+	ExceptionIllegalFunction        ExceptionCode = 0x01
+	ExceptionIllegalDataAddress     ExceptionCode = 0x02
+	ExceptionIllegalDataValue       ExceptionCode = 0x03
+	ExceptionSlaveDeviceFailure     ExceptionCode = 0x04
+	ExceptionAcknowledge            ExceptionCode = 0x05
+	ExceptionSlaveDeviceBusy        ExceptionCode = 0x06
+	ExceptionNegativeAcknowledge    ExceptionCode = 0x07
+	ExceptionMemoryParityError      ExceptionCode = 0x08
+	ExceptionGatewayPathUnavailable ExceptionCode = 0x0A
+	ExceptionGatewayTargetFailed    ExceptionCode = 0x0B
+)
+
+func (e ExceptionCode) String() string {
+	switch e {
+	case ExceptionIllegalFunction:
+		return "illegal function"
+	case ExceptionIllegalDataAddress:
+		return "illegal data address"
+	case ExceptionIllegalDataValue:
+		return "illegal data value"
+	case ExceptionSlaveDeviceFailure:
+		return "slave device failure"
+	case ExceptionAcknowledge:
+		return "acknowledge"
+	case ExceptionSlaveDeviceBusy:
+		return "slave device busy"
+	case ExceptionNegativeAcknowledge:
+		return "negative acknowledge"
+	case ExceptionMemoryParityError:
+		return "memory parity error"
+	case ExceptionGatewayPathUnavailable:
+		return "gateway path unavailable"
+	case ExceptionGatewayTargetFailed:
+		return "gateway target device failed to respond"
+	default:
+		return fmt.Sprintf("unknown exception code %#x", byte(e))
+	}
+}
+
+// ExceptionError is returned when a slave responds to Function with the
+// Modbus exception bit (0x80) set.
+type ExceptionError struct {
+	Function byte
+	Code     ExceptionCode
+}
+
+func (e *ExceptionError) Error() string {
+	return fmt.Sprintf("modbus: function %#x: %s", e.Function, e.Code)
+}
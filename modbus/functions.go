@@ -0,0 +1,88 @@
+package modbus
+
+import "fmt"
+
+// Function codes defined by the Modbus Application Protocol spec that
+// this package implements.
+const (
+	FuncReadCoils              = 0x01
+	FuncReadInputRegisters     = 0x04
+	FuncReadHoldingRegisters   = 0x03
+	FuncWriteSingleRegister    = 0x06
+	FuncWriteMultipleRegisters = 0x10
+)
+
+// ReadCoils reads quantity coils starting at address from slaveID and
+// returns one bool per coil.
+func (c *Client) ReadCoils(slaveID byte, address, quantity uint16) ([]bool, error) {
+	pdu := []byte{FuncReadCoils, byte(address >> 8), byte(address), byte(quantity >> 8), byte(quantity)}
+	resp, err := c.do(slaveID, pdu)
+	if err != nil {
+		return nil, err
+	}
+	if len(resp) < 1 || len(resp) != 1+int(resp[0]) {
+		return nil, fmt.Errorf("modbus: malformed ReadCoils response")
+	}
+	byteCount := resp[0]
+	data := resp[1:]
+	coils := make([]bool, quantity)
+	for i := uint16(0); i < quantity; i++ {
+		byteIdx := i / 8
+		if int(byteIdx) >= int(byteCount) {
+			break
+		}
+		coils[i] = data[byteIdx]&(1<<(i%8)) != 0
+	}
+	return coils, nil
+}
+
+// ReadHoldingRegisters reads quantity 16-bit holding registers starting
+// at address from slaveID.
+func (c *Client) ReadHoldingRegisters(slaveID byte, address, quantity uint16) ([]uint16, error) {
+	return c.readRegisters(slaveID, FuncReadHoldingRegisters, address, quantity)
+}
+
+// ReadInputRegisters reads quantity 16-bit input registers starting at
+// address from slaveID.
+func (c *Client) ReadInputRegisters(slaveID byte, address, quantity uint16) ([]uint16, error) {
+	return c.readRegisters(slaveID, FuncReadInputRegisters, address, quantity)
+}
+
+func (c *Client) readRegisters(slaveID byte, fc byte, address, quantity uint16) ([]uint16, error) {
+	pdu := []byte{fc, byte(address >> 8), byte(address), byte(quantity >> 8), byte(quantity)}
+	resp, err := c.do(slaveID, pdu)
+	if err != nil {
+		return nil, err
+	}
+	if len(resp) < 1 || len(resp) != 1+int(resp[0]) || resp[0] != byte(2*quantity) {
+		return nil, fmt.Errorf("modbus: malformed register read response")
+	}
+	data := resp[1:]
+	regs := make([]uint16, quantity)
+	for i := range regs {
+		regs[i] = uint16(data[2*i])<<8 | uint16(data[2*i+1])
+	}
+	return regs, nil
+}
+
+// WriteSingleRegister writes value to the holding register at address on
+// slaveID.
+func (c *Client) WriteSingleRegister(slaveID byte, address, value uint16) error {
+	pdu := []byte{FuncWriteSingleRegister, byte(address >> 8), byte(address), byte(value >> 8), byte(value)}
+	_, err := c.do(slaveID, pdu)
+	return err
+}
+
+// WriteMultipleRegisters writes values to the holding registers starting
+// at address on slaveID.
+func (c *Client) WriteMultipleRegisters(slaveID byte, address uint16, values []uint16) error {
+	pdu := make([]byte, 0, 6+2*len(values))
+	pdu = append(pdu, FuncWriteMultipleRegisters, byte(address>>8), byte(address))
+	quantity := uint16(len(values))
+	pdu = append(pdu, byte(quantity>>8), byte(quantity), byte(2*len(values)))
+	for _, v := range values {
+		pdu = append(pdu, byte(v>>8), byte(v))
+	}
+	_, err := c.do(slaveID, pdu)
+	return err
+}
@@ -0,0 +1,207 @@
+// Package modbus implements Modbus RTU and ASCII client (master)
+// transports on top of a serial connection opened with the serial
+// package.
+//
+// A Client wraps the io.ReadWriteCloser returned by serial.OpenPort and
+// speaks either binary RTU framing (CRC-16) or ':'-prefixed hex ASCII
+// framing (LRC) to one or more slave devices sharing the same link.
+package modbus
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"time"
+)
+
+// Mode selects the wire framing used to talk to the slave(s).
+type Mode int
+
+const (
+	// RTU is the binary framing variant, delimited by a 3.5 character
+	// silence on the line and protected by a CRC-16 checksum.
+	RTU Mode = iota
+	// ASCII is the human-readable framing variant, delimited by ':' and
+	// "\r\n" and protected by a longitudinal redundancy check (LRC).
+	ASCII
+)
+
+// timeoutSetter is implemented by serial.Port. When the port passed to
+// NewClient implements it, the client also configures the port's own
+// read timeout as an optimization, so a real serial.Port returns from a
+// blocked Read promptly instead of only via readWithDeadline's
+// goroutine. Correctness of the overall response/silence timeouts does
+// not depend on this; it holds for any io.ReadWriteCloser.
+type timeoutSetter interface {
+	SetReadTimeout(time.Duration)
+}
+
+// defaultResponseTimeout is how long the client waits for a slave to
+// start responding at all before giving up, independent of the much
+// shorter RTU inter-frame silence used only to detect the end of a
+// frame that has already started. It is generous enough for real
+// slaves, whose turnaround is typically tens of milliseconds.
+const defaultResponseTimeout = 1 * time.Second
+
+// asciiPollInterval is the per-Read timeout set on the port in ASCII
+// mode. ASCII framing has no inter-character timing requirement, so
+// unlike RTU this is just a poll granularity for noticing that
+// responseTimeout has elapsed while waiting on the ':' delimiter.
+const asciiPollInterval = 100 * time.Millisecond
+
+// Client is a Modbus client (master). It is safe for use by a single
+// goroutine at a time; callers that need concurrent access should
+// serialize requests themselves, since the underlying link is shared.
+type Client struct {
+	port            io.ReadWriteCloser
+	mode            Mode
+	silence         time.Duration
+	responseTimeout time.Duration
+}
+
+// NewClient returns a Client that issues requests over port using the
+// given Mode. baud is the configured line speed and is used only to
+// compute the RTU inter-frame silence; it is ignored in ASCII mode. The
+// response timeout, i.e. how long to wait for a slave to start
+// replying, defaults to defaultResponseTimeout and can be changed with
+// SetResponseTimeout.
+func NewClient(port io.ReadWriteCloser, baud int, mode Mode) *Client {
+	c := &Client{
+		port:            port,
+		mode:            mode,
+		silence:         interFrameSilence(baud),
+		responseTimeout: defaultResponseTimeout,
+	}
+	if ts, ok := port.(timeoutSetter); ok {
+		switch mode {
+		case RTU:
+			ts.SetReadTimeout(c.silence)
+		case ASCII:
+			ts.SetReadTimeout(asciiPollInterval)
+		}
+	}
+	return c
+}
+
+// SetResponseTimeout changes how long the client waits for a slave to
+// start responding before giving up. The default is defaultResponseTimeout.
+func (c *Client) SetResponseTimeout(d time.Duration) {
+	c.responseTimeout = d
+}
+
+// do sends a PDU (function code followed by its data) to slaveID, frames
+// it per c.mode, and returns the PDU of the response with the function
+// code and slave ID stripped off.
+func (c *Client) do(slaveID byte, pdu []byte) ([]byte, error) {
+	var frame []byte
+	switch c.mode {
+	case RTU:
+		frame = encodeRTU(slaveID, pdu)
+	case ASCII:
+		frame = encodeASCII(slaveID, pdu)
+	default:
+		return nil, fmt.Errorf("modbus: unknown mode %v", c.mode)
+	}
+
+	if _, err := c.port.Write(frame); err != nil {
+		return nil, fmt.Errorf("modbus: write: %w", err)
+	}
+
+	var respID byte
+	var respPDU []byte
+	var err error
+	switch c.mode {
+	case RTU:
+		respID, respPDU, err = readRTU(c.port, c.silence, c.responseTimeout)
+	case ASCII:
+		respID, respPDU, err = readASCII(c.port, c.responseTimeout)
+	}
+	if err != nil {
+		return nil, err
+	}
+	if respID != slaveID {
+		return nil, fmt.Errorf("modbus: response from slave %d, expected %d", respID, slaveID)
+	}
+	if len(respPDU) == 0 {
+		return nil, fmt.Errorf("modbus: empty response")
+	}
+
+	fc := pdu[0]
+	if respPDU[0] == fc|0x80 {
+		if len(respPDU) < 2 {
+			return nil, fmt.Errorf("modbus: exception response missing code")
+		}
+		return nil, &ExceptionError{Function: fc, Code: ExceptionCode(respPDU[1])}
+	}
+	if respPDU[0] != fc {
+		return nil, fmt.Errorf("modbus: response function code %#x, expected %#x", respPDU[0], fc)
+	}
+	return respPDU[1:], nil
+}
+
+// charDuration is the time it takes to transmit one serial character
+// (start bit + 8 data bits + parity/stop + stop bit, i.e. 11 bits) at
+// baud.
+func charDuration(baud int) time.Duration {
+	if baud <= 0 {
+		baud = 9600
+	}
+	return time.Duration(11*1e9/int64(baud)) * time.Nanosecond
+}
+
+// interFrameSilence returns the minimum RTU inter-frame silence for baud,
+// per the Modbus over Serial Line specification: 3.5 character times
+// below 19200 baud, and a fixed 1750us above it (since the 3.5-character
+// figure would otherwise shrink below what real UARTs can reliably gap).
+func interFrameSilence(baud int) time.Duration {
+	if baud > 19200 {
+		return 1750 * time.Microsecond
+	}
+	return time.Duration(3.5 * float64(charDuration(baud)))
+}
+
+// errReadDeadlineExceeded is returned by readWithDeadline when deadline
+// passes before r.Read produces anything, regardless of whether r ever
+// honors the port's own SetReadTimeout.
+var errReadDeadlineExceeded = errors.New("modbus: read deadline exceeded")
+
+// readWithDeadline performs a single Read on r, guaranteeing a return
+// by deadline even if r.Read itself blocks indefinitely (a net.Conn
+// with no deadline, an io.Pipe with no writer, etc.) — unlike relying
+// on a serial.Port's own SetReadTimeout, which only bounds Read for
+// that concrete type. Since Go cannot interrupt an in-flight Read on an
+// arbitrary io.Reader, the read runs in a background goroutine against
+// its own private buffer; if deadline wins the race, that goroutine is
+// abandoned rather than awaited, and its eventual result is discarded.
+func readWithDeadline(r io.Reader, p []byte, deadline time.Time) (int, error) {
+	type result struct {
+		n   int
+		err error
+	}
+	done := make(chan result, 1)
+	tmp := make([]byte, len(p))
+	go func() {
+		n, err := r.Read(tmp)
+		done <- result{n, err}
+	}()
+
+	select {
+	case res := <-done:
+		copy(p, tmp[:res.n])
+		return res.n, res.err
+	case <-time.After(time.Until(deadline)):
+		return 0, errReadDeadlineExceeded
+	}
+}
+
+// deadlineReader adapts an arbitrary io.Reader to bufio.Reader's
+// expectations while bounding every Read by a fixed deadline via
+// readWithDeadline.
+type deadlineReader struct {
+	r        io.Reader
+	deadline time.Time
+}
+
+func (d *deadlineReader) Read(p []byte) (int, error) {
+	return readWithDeadline(d.r, p, d.deadline)
+}
@@ -0,0 +1,108 @@
+package modbus
+
+import (
+	"bytes"
+	"testing"
+	"time"
+)
+
+// fakePort is a minimal io.ReadWriteCloser that lets a test hand the
+// Client a canned response without a real serial.Port. It deliberately
+// does not implement timeoutSetter, so NewClient exercises the same
+// path a plain io.ReadWriteCloser would.
+type fakePort struct {
+	written bytes.Buffer
+	read    *bytes.Reader
+}
+
+func newFakePort(response []byte) *fakePort {
+	return &fakePort{read: bytes.NewReader(response)}
+}
+
+func (f *fakePort) Write(p []byte) (int, error) { return f.written.Write(p) }
+func (f *fakePort) Read(p []byte) (int, error)  { return f.read.Read(p) }
+func (f *fakePort) Close() error                { return nil }
+
+func TestClientReadCoilsDecodesResponse(t *testing.T) {
+	// byte count 1, data 0x05 -> coils 0 and 2 set.
+	resp := encodeRTU(0x01, []byte{FuncReadCoils, 0x01, 0x05})
+	port := newFakePort(resp)
+	c := NewClient(port, 19200, RTU)
+
+	coils, err := c.ReadCoils(0x01, 0, 4)
+	if err != nil {
+		t.Fatalf("ReadCoils() unexpected error: %v", err)
+	}
+	want := []bool{true, false, true, false}
+	if len(coils) != len(want) {
+		t.Fatalf("ReadCoils() = %v, want %v", coils, want)
+	}
+	for i := range want {
+		if coils[i] != want[i] {
+			t.Fatalf("ReadCoils() = %v, want %v", coils, want)
+		}
+	}
+}
+
+func TestClientReadHoldingRegistersDecodesResponse(t *testing.T) {
+	resp := encodeRTU(0x01, []byte{FuncReadHoldingRegisters, 0x04, 0x00, 0x2A, 0x01, 0x00})
+	port := newFakePort(resp)
+	c := NewClient(port, 19200, RTU)
+
+	regs, err := c.ReadHoldingRegisters(0x01, 0, 2)
+	if err != nil {
+		t.Fatalf("ReadHoldingRegisters() unexpected error: %v", err)
+	}
+	want := []uint16{0x002A, 0x0100}
+	if len(regs) != len(want) || regs[0] != want[0] || regs[1] != want[1] {
+		t.Fatalf("ReadHoldingRegisters() = %v, want %v", regs, want)
+	}
+}
+
+func TestClientDoDecodesExceptionResponse(t *testing.T) {
+	resp := encodeRTU(0x01, []byte{FuncReadCoils | 0x80, byte(ExceptionIllegalDataAddress)})
+	port := newFakePort(resp)
+	c := NewClient(port, 19200, RTU)
+
+	_, err := c.ReadCoils(0x01, 0, 4)
+	if err == nil {
+		t.Fatal("ReadCoils() expected an exception error, got nil")
+	}
+	exc, ok := err.(*ExceptionError)
+	if !ok {
+		t.Fatalf("ReadCoils() error = %T, want *ExceptionError", err)
+	}
+	if exc.Function != FuncReadCoils || exc.Code != ExceptionIllegalDataAddress {
+		t.Fatalf("ReadCoils() exception = %+v, want Function=%#x Code=%v", exc, FuncReadCoils, ExceptionIllegalDataAddress)
+	}
+}
+
+func TestClientDoRejectsMismatchedSlaveID(t *testing.T) {
+	resp := encodeRTU(0x02, []byte{FuncReadCoils, 0x01, 0x00})
+	port := newFakePort(resp)
+	c := NewClient(port, 19200, RTU)
+
+	if _, err := c.ReadCoils(0x01, 0, 4); err == nil {
+		t.Fatal("ReadCoils() expected an error for a response from the wrong slave, got nil")
+	}
+}
+
+func TestSetResponseTimeoutOverridesDefault(t *testing.T) {
+	c := NewClient(newFakePort(nil), 19200, RTU)
+	if c.responseTimeout != defaultResponseTimeout {
+		t.Fatalf("NewClient() responseTimeout = %v, want default %v", c.responseTimeout, defaultResponseTimeout)
+	}
+	c.SetResponseTimeout(5 * time.Second)
+	if c.responseTimeout != 5*time.Second {
+		t.Fatalf("SetResponseTimeout() responseTimeout = %v, want 5s", c.responseTimeout)
+	}
+}
+
+func TestInterFrameSilence(t *testing.T) {
+	if got := interFrameSilence(115200); got != 1750*time.Microsecond {
+		t.Fatalf("interFrameSilence(115200) = %v, want 1750us (fixed above 19200 baud)", got)
+	}
+	if got := interFrameSilence(9600); got <= 0 {
+		t.Fatalf("interFrameSilence(9600) = %v, want a positive duration", got)
+	}
+}
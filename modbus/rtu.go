@@ -0,0 +1,98 @@
+package modbus
+
+import (
+	"fmt"
+	"io"
+	"time"
+)
+
+// rtuMinSize is the shortest possible RTU frame: slave ID + function code
+// + 2 byte CRC.
+const rtuMinSize = 4
+
+// encodeRTU builds a binary RTU frame: slave ID, PDU, and a little-endian
+// CRC-16 over both.
+func encodeRTU(slaveID byte, pdu []byte) []byte {
+	frame := make([]byte, 0, 1+len(pdu)+2)
+	frame = append(frame, slaveID)
+	frame = append(frame, pdu...)
+	crc := crc16(frame)
+	frame = append(frame, byte(crc), byte(crc>>8))
+	return frame
+}
+
+// readRTU reads one RTU frame from r. Per the Modbus over Serial Line
+// spec, frames are delimited purely by the inter-frame silence rather
+// than a length field or terminator, so readRTU keeps reading until a
+// read takes at least silence with no new bytes, then validates the CRC
+// of whatever was accumulated. responseTimeout bounds how long it waits
+// for the slave to start replying at all, before the first byte of the
+// frame has arrived; it is unrelated to silence, which only detects the
+// end of a frame that has already started.
+func readRTU(r io.Reader, silence, responseTimeout time.Duration) (slaveID byte, pdu []byte, err error) {
+	frame, err := readUntilSilent(r, silence, responseTimeout)
+	if err != nil {
+		return 0, nil, err
+	}
+	if len(frame) < rtuMinSize {
+		return 0, nil, fmt.Errorf("modbus: RTU frame too short (%d bytes)", len(frame))
+	}
+
+	want := crc16(frame[:len(frame)-2])
+	got := uint16(frame[len(frame)-2]) | uint16(frame[len(frame)-1])<<8
+	if want != got {
+		return 0, nil, fmt.Errorf("modbus: RTU CRC mismatch (got %#04x, want %#04x)", got, want)
+	}
+
+	return frame[0], frame[1 : len(frame)-2], nil
+}
+
+// readUntilSilent accumulates bytes from r, first waiting up to
+// responseTimeout for the slave to start replying, then switching to
+// the much shorter silence once data has started arriving so that a
+// read with no new bytes within silence is treated as the end of the
+// frame. Each Read is bounded by readWithDeadline rather than relying
+// on r's own timeout, so this terminates even if r blocks natively (a
+// serial.Port configured via NewClient will also time out its Read on
+// its own, well inside the deadline, but that is an optimization, not
+// something readUntilSilent depends on for correctness).
+func readUntilSilent(r io.Reader, silence, responseTimeout time.Duration) ([]byte, error) {
+	var frame []byte
+	deadline := time.Now().Add(responseTimeout)
+	for {
+		buf := make([]byte, 256)
+		n, err := readWithDeadline(r, buf, deadline)
+		if n > 0 {
+			frame = append(frame, buf[:n]...)
+			deadline = time.Now().Add(silence)
+			continue
+		}
+		if err == errReadDeadlineExceeded {
+			if len(frame) == 0 {
+				return nil, fmt.Errorf("modbus: timed out waiting for response")
+			}
+			return frame, nil
+		}
+		if err != nil && err != io.EOF {
+			return nil, fmt.Errorf("modbus: read: %w", err)
+		}
+	}
+}
+
+// crc16 computes the Modbus CRC-16 (polynomial 0xA001, init 0xFFFF) over
+// data.
+func crc16(data []byte) uint16 {
+	crc := uint16(0xFFFF)
+	for _, b := range data {
+		crc ^= uint16(b)
+		for i := 0; i < 8; i++ {
+			if crc&1 != 0 {
+				crc >>= 1
+				crc ^= 0xA001
+			} else {
+				crc >>= 1
+			}
+		}
+	}
+	return crc
+}
@@ -0,0 +1,80 @@
+package modbus
+
+import (
+	"bytes"
+	"io"
+	"testing"
+	"time"
+)
+
+// crc16 test vector taken straight from the Modbus over Serial Line
+// specification's worked example: a ReadHoldingRegisters request for
+// slave 1, starting address 0, quantity 10.
+func TestCRC16KnownVector(t *testing.T) {
+	got := crc16([]byte{0x01, 0x03, 0x00, 0x00, 0x00, 0x0A})
+	want := uint16(0xC5) | uint16(0xCD)<<8
+	if got != want {
+		t.Fatalf("crc16() = %#04x, want %#04x", got, want)
+	}
+}
+
+func TestEncodeRTUAppendsLittleEndianCRC(t *testing.T) {
+	frame := encodeRTU(0x01, []byte{0x03, 0x00, 0x00, 0x00, 0x0A})
+	want := []byte{0x01, 0x03, 0x00, 0x00, 0x00, 0x0A, 0xC5, 0xCD}
+	if !bytes.Equal(frame, want) {
+		t.Fatalf("encodeRTU() = % x, want % x", frame, want)
+	}
+}
+
+func TestReadRTURoundTrip(t *testing.T) {
+	frame := encodeRTU(0x11, []byte{0x03, 0x02, 0x00, 0x2A})
+	slaveID, pdu, err := readRTU(bytes.NewReader(frame), time.Millisecond, time.Second)
+	if err != nil {
+		t.Fatalf("readRTU() unexpected error: %v", err)
+	}
+	if slaveID != 0x11 {
+		t.Fatalf("readRTU() slaveID = %#x, want 0x11", slaveID)
+	}
+	want := []byte{0x03, 0x02, 0x00, 0x2A}
+	if !bytes.Equal(pdu, want) {
+		t.Fatalf("readRTU() pdu = % x, want % x", pdu, want)
+	}
+}
+
+func TestReadRTUTooShort(t *testing.T) {
+	_, _, err := readRTU(bytes.NewReader([]byte{0x01, 0x02}), time.Millisecond, time.Second)
+	if err == nil {
+		t.Fatal("readRTU() expected error for an undersized frame, got nil")
+	}
+}
+
+func TestReadRTUCRCMismatch(t *testing.T) {
+	frame := encodeRTU(0x11, []byte{0x03, 0x02, 0x00, 0x2A})
+	frame[len(frame)-1] ^= 0xFF // corrupt the CRC
+	_, _, err := readRTU(bytes.NewReader(frame), time.Millisecond, time.Second)
+	if err == nil {
+		t.Fatal("readRTU() expected a CRC mismatch error, got nil")
+	}
+}
+
+// TestReadRTUTimesOutOnBlockingReader guards against the bug where
+// readUntilSilent only enforced responseTimeout if the underlying
+// Reader itself returned promptly (as a serial.Port does via its own
+// SetReadTimeout). An io.Pipe with no writer blocks natively, so this
+// would previously hang forever; readRTU must still return within
+// roughly responseTimeout.
+func TestReadRTUTimesOutOnBlockingReader(t *testing.T) {
+	pr, pw := io.Pipe()
+	defer pw.Close()
+
+	start := time.Now()
+	_, _, err := readRTU(pr, time.Millisecond, 20*time.Millisecond)
+	elapsed := time.Since(start)
+
+	if err == nil {
+		t.Fatal("readRTU() expected a timeout error, got nil")
+	}
+	if elapsed > time.Second {
+		t.Fatalf("readRTU() took %v to time out, want well under 1s", elapsed)
+	}
+}
@@ -13,8 +13,9 @@
 	Currently there is very little in the way of configurability.  You can
 	set the baud rate.  Then you can Read(), Write(), or Close() the
 	connection.  Read() will block until at least one byte is returned.
-	Write is the same.  There is currently no exposed way to set the
-	timeouts, though patches are welcome.
+	Write is the same.  The Port interface returned by OpenPort also
+	exposes SetReadTimeout, Flush, SendBreak and the modem control lines
+	(RTS/DTR) for callers that need them.
 
 	Currently all ports are opened with 8 data bits, 1 stop bit, no
 	parity, no hardware flow control, and no software flow control.  This
@@ -65,7 +66,10 @@
 */
 package serial
 
-import "io"
+import (
+	"io"
+	"time"
+)
 
 const (
 	RTS_FLAG     = 0
@@ -108,8 +112,66 @@ type Config struct {
 	Timeout int
 }
 
+// Port is the interface satisfied by an open serial port. Besides the
+// basic byte stream it exposes the handful of line-level controls that
+// both the Windows and POSIX implementations are able to support.
+type Port interface {
+	io.ReadWriteCloser
+
+	// SetReadTimeout bounds how long Read will block waiting for data
+	// to arrive before returning. A zero duration disables the timeout:
+	// on POSIX, Read then blocks until at least one byte is available;
+	// on Windows, it blocks until the whole buffer has been filled.
+	SetReadTimeout(d time.Duration)
+
+	// Flush waits for any buffered output to finish transmitting and
+	// discards any input that has been received but not yet read.
+	Flush() error
+
+	// SendBreak transmits a break condition (a continuous space, or
+	// logic 0) on the line for at least d.
+	SendBreak(d time.Duration) error
+
+	// SetRTS asserts or clears the RTS (Request To Send) modem control
+	// line. Half-duplex RS-485 transceivers typically toggle this
+	// around each write to switch the transceiver's driver direction.
+	SetRTS(on bool) error
+
+	// SetDTR asserts or clears the DTR (Data Terminal Ready) modem
+	// control line.
+	SetDTR(on bool) error
+
+	// Status reports the current state of the modem status input
+	// lines (CTS, DSR, DCD and RI).
+	Status() (ModemStatus, error)
+
+	// WaitForEvent blocks until one of the events in mask occurs, and
+	// returns the subset of mask that actually fired.
+	WaitForEvent(mask EventMask) (EventMask, error)
+}
+
+// ModemStatus reports the state of the modem status input lines.
+type ModemStatus struct {
+	CTS bool // Clear To Send
+	DSR bool // Data Set Ready
+	DCD bool // Data Carrier Detect (a.k.a. RLSD)
+	RI  bool // Ring Indicator
+}
+
+// EventMask is a bitmask of line events that WaitForEvent can wait on.
+type EventMask uint32
+
+const (
+	EventCTS   EventMask = 1 << iota // CTS line changed state
+	EventDSR                         // DSR line changed state
+	EventDCD                         // DCD (RLSD) line changed state
+	EventRing                        // Ring indicator detected
+	EventBreak                       // A break condition was received
+	EventError                       // A line status error occurred
+)
+
 // OpenPort opens a serial port with the specified configuration
-func OpenPort(c *Config) (io.ReadWriteCloser, error) {
+func OpenPort(c *Config) (Port, error) {
 	spec := make([]byte, 3)
 	spec[0] = byte(c.Size)
 	if spec[0] == byte(0) {
@@ -124,8 +186,25 @@ func OpenPort(c *Config) (io.ReadWriteCloser, error) {
 	return openPort(c.Name, c.Baud, spec, flow)
 }
 
-// func Flush()
+// PortInfo describes a serial port discovered by ListPorts. Fields that
+// the platform or the device itself does not report (most commonly
+// Manufacturer, Product, USBVendorID, USBProductID and SerialNumber for
+// a port with no USB parent) are left as the empty string.
+type PortInfo struct {
+	Name         string
+	Description  string
+	Manufacturer string
+	Product      string
+	USBVendorID  string
+	USBProductID string
+	SerialNumber string
+}
 
-// func SendBreak()
+// ListPorts returns the serial ports currently present on the system,
+// along with whatever USB identification metadata the platform exposes
+// for each.
+func ListPorts() ([]PortInfo, error) {
+	return listPorts()
+}
 
 // func RegisterBreakHandler(func())
@@ -0,0 +1,24 @@
+// +build darwin
+
+package serial
+
+import (
+	"syscall"
+	"unsafe"
+)
+
+// iossiospeed is IOSSIOSPEED from <IOKit/serial/ioss.h>: it sets the
+// tty's baud rate to an arbitrary integer, bypassing the fixed Bxxxxx
+// constants that cfsetispeed/cfsetospeed are limited to.
+const iossiospeed = 0x80045402
+
+// setArbitraryBaud overrides the rate openPort already set via the
+// normal tcsetattr path with the exact integer baud requested.
+func setArbitraryBaud(fd uintptr, baud int) error {
+	speed := int32(baud)
+	_, _, errno := syscall.Syscall(syscall.SYS_IOCTL, fd, uintptr(iossiospeed), uintptr(unsafe.Pointer(&speed)))
+	if errno != 0 {
+		return errno
+	}
+	return nil
+}
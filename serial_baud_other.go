@@ -0,0 +1,12 @@
+// +build !windows,!linux,!darwin
+
+package serial
+
+import "fmt"
+
+// setArbitraryBaud is a stub for POSIX platforms other than Linux and
+// Darwin, which this package does not yet know a non-standard-baud
+// ioctl for.
+func setArbitraryBaud(fd uintptr, baud int) error {
+	return fmt.Errorf("serial: baud rate %d is not one of the standard rates, and arbitrary rates are not supported on this platform", baud)
+}
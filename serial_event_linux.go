@@ -0,0 +1,59 @@
+// +build linux
+
+package serial
+
+import "syscall"
+
+// tiocmiwait blocks the calling goroutine until one of the TIOCM_* bits
+// passed in changes state; it is Linux-only.
+const tiocmiwait = 0x545C
+
+// WaitForEvent blocks until one of the modem status lines in mask
+// changes state, using TIOCMIWAIT so the wait happens in the kernel
+// instead of by polling. Break and line-status-error conditions are not
+// reported by TIOCMIWAIT, so EventBreak and EventError never fire here.
+func (p *serialPort) WaitForEvent(mask EventMask) (EventMask, error) {
+	var bits uint32
+	if mask&EventCTS != 0 {
+		bits |= tiocmCTS
+	}
+	if mask&EventDSR != 0 {
+		bits |= tiocmDSR
+	}
+	if mask&EventDCD != 0 {
+		bits |= tiocmCAR
+	}
+	if mask&EventRing != 0 {
+		bits |= tiocmRNG
+	}
+
+	before, err := p.Status()
+	if err != nil {
+		return 0, err
+	}
+
+	_, _, errno := syscall.Syscall(syscall.SYS_IOCTL, p.f.Fd(), tiocmiwait, uintptr(bits))
+	if errno != 0 {
+		return 0, errno
+	}
+
+	after, err := p.Status()
+	if err != nil {
+		return 0, err
+	}
+
+	var fired EventMask
+	if mask&EventCTS != 0 && before.CTS != after.CTS {
+		fired |= EventCTS
+	}
+	if mask&EventDSR != 0 && before.DSR != after.DSR {
+		fired |= EventDSR
+	}
+	if mask&EventDCD != 0 && before.DCD != after.DCD {
+		fired |= EventDCD
+	}
+	if mask&EventRing != 0 && before.RI != after.RI {
+		fired |= EventRing
+	}
+	return fired, nil
+}
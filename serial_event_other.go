@@ -0,0 +1,48 @@
+// +build !windows,!linux
+
+package serial
+
+import "time"
+
+// pollInterval is how often WaitForEvent samples the modem status lines
+// on platforms with no blocking wait primitive for them, such as
+// Linux's TIOCMIWAIT.
+const pollInterval = 50 * time.Millisecond
+
+// WaitForEvent polls Status at pollInterval until one of the lines in
+// mask changes state. EventBreak and EventError never fire, since a
+// transient break or line error cannot be observed by sampling the
+// modem status lines.
+func (p *serialPort) WaitForEvent(mask EventMask) (EventMask, error) {
+	before, err := p.Status()
+	if err != nil {
+		return 0, err
+	}
+
+	for {
+		time.Sleep(pollInterval)
+
+		after, err := p.Status()
+		if err != nil {
+			return 0, err
+		}
+
+		var fired EventMask
+		if mask&EventCTS != 0 && before.CTS != after.CTS {
+			fired |= EventCTS
+		}
+		if mask&EventDSR != 0 && before.DSR != after.DSR {
+			fired |= EventDSR
+		}
+		if mask&EventDCD != 0 && before.DCD != after.DCD {
+			fired |= EventDCD
+		}
+		if mask&EventRing != 0 && before.RI != after.RI {
+			fired |= EventRing
+		}
+		if fired != 0 {
+			return fired, nil
+		}
+		before = after
+	}
+}
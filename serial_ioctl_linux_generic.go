@@ -0,0 +1,17 @@
+// +build linux
+// +build !mips
+// +build !mipsle
+// +build !mips64
+// +build !mips64le
+// +build !ppc
+// +build !ppc64
+// +build !ppc64le
+
+package serial
+
+// TCGETS2/TCSETS2 ioctl request numbers for the architectures that use
+// the "generic" ioctl direction encoding (x86, arm, arm64, etc).
+const (
+	tcgets2 = 0x802c542a
+	tcsets2 = 0x402c542b
+)
@@ -0,0 +1,13 @@
+// +build linux
+// +build mips mips64 mipsle mips64le ppc ppc64 ppc64le
+
+package serial
+
+// TCGETS2/TCSETS2 ioctl request numbers for the architectures whose
+// ioctl direction bits are encoded the other way round from the
+// "generic" layout (mips and ppc), which swaps which of the two
+// requests carries the read/write direction bit relative to x86/arm.
+const (
+	tcgets2 = 0x402c542a
+	tcsets2 = 0x802c542b
+)
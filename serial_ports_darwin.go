@@ -0,0 +1,146 @@
+// +build darwin
+
+package serial
+
+/*
+#cgo LDFLAGS: -framework CoreFoundation -framework IOKit
+
+#include <CoreFoundation/CoreFoundation.h>
+#include <IOKit/IOKitLib.h>
+#include <IOKit/serial/IOSerialKeys.h>
+#include <stdlib.h>
+
+static char *cfStringProperty(io_registry_entry_t entry, const char *key) {
+	CFStringRef cfKey = CFStringCreateWithCString(kCFAllocatorDefault, key, kCFStringEncodingUTF8);
+	CFTypeRef value = IORegistryEntryCreateCFProperty(entry, cfKey, kCFAllocatorDefault, 0);
+	CFRelease(cfKey);
+	if (value == NULL || CFGetTypeID(value) != CFStringGetTypeID()) {
+		if (value != NULL) {
+			CFRelease(value);
+		}
+		return NULL;
+	}
+
+	CFStringRef str = (CFStringRef)value;
+	CFIndex length = CFStringGetMaximumSizeForEncoding(CFStringGetLength(str), kCFStringEncodingUTF8) + 1;
+	char *buf = malloc(length);
+	if (!CFStringGetCString(str, buf, length, kCFStringEncodingUTF8)) {
+		free(buf);
+		buf = NULL;
+	}
+	CFRelease(value);
+	return buf;
+}
+
+static int cfIntProperty(io_registry_entry_t entry, const char *key, int *out) {
+	CFStringRef cfKey = CFStringCreateWithCString(kCFAllocatorDefault, key, kCFStringEncodingUTF8);
+	CFTypeRef value = IORegistryEntryCreateCFProperty(entry, cfKey, kCFAllocatorDefault, 0);
+	CFRelease(cfKey);
+	if (value == NULL || CFGetTypeID(value) != CFNumberGetTypeID()) {
+		if (value != NULL) {
+			CFRelease(value);
+		}
+		return 0;
+	}
+
+	int ok = CFNumberGetValue((CFNumberRef)value, kCFNumberIntType, out) ? 1 : 0;
+	CFRelease(value);
+	return ok;
+}
+
+// isUSBDevice reports whether entry conforms to the IOUSBDevice (or
+// modern IOUSBHostDevice) service class, i.e. whether it is the USB
+// device node itself rather than one of its children or grandchildren.
+static int isUSBDevice(io_registry_entry_t entry) {
+	return IOObjectConformsTo(entry, "IOUSBDevice") || IOObjectConformsTo(entry, "IOUSBHostDevice");
+}
+*/
+import "C"
+
+import (
+	"fmt"
+	"unsafe"
+)
+
+// listPorts enumerates IOSerialBSDClient services, then walks each
+// one's IORegistry parent chain up to the owning USB device (if any) to
+// collect vendor/product metadata.
+func listPorts() ([]PortInfo, error) {
+	cClass := C.CString(C.kIOSerialBSDServiceValue)
+	defer C.free(unsafe.Pointer(cClass))
+	matching := C.IOServiceMatching(cClass)
+
+	var iter C.io_iterator_t
+	if kr := C.IOServiceGetMatchingServices(C.kIOMasterPortDefault, matching, &iter); kr != C.KERN_SUCCESS {
+		return nil, fmt.Errorf("serial: IOServiceGetMatchingServices failed: %d", int(kr))
+	}
+	defer C.IOObjectRelease(C.io_object_t(iter))
+
+	var ports []PortInfo
+	for {
+		service := C.IOIteratorNext(iter)
+		if service == 0 {
+			break
+		}
+
+		ports = append(ports, portInfoForService(service))
+		C.IOObjectRelease(service)
+	}
+	return ports, nil
+}
+
+func portInfoForService(service C.io_object_t) PortInfo {
+	info := PortInfo{Name: stringProperty(service, C.kIOCalloutDeviceKey)}
+
+	// Walk up the registry tree looking for the USB device node that
+	// owns this serial port; not every serial port has one (e.g. a
+	// Bluetooth modem, or a system's built-in debug console).
+	child := service
+	for {
+		var parent C.io_registry_entry_t
+		kr := C.IORegistryEntryGetParentEntry(child, C.kIOServicePlane, &parent)
+		if child != service {
+			C.IOObjectRelease(C.io_object_t(child))
+		}
+		if kr != C.KERN_SUCCESS {
+			break
+		}
+		child = parent
+
+		if C.isUSBDevice(C.io_registry_entry_t(child)) != 0 {
+			info.Manufacturer = stringProperty(child, "USB Vendor Name")
+			info.Product = stringProperty(child, "USB Product Name")
+			info.SerialNumber = stringProperty(child, "USB Serial Number")
+			info.USBVendorID = hexIntProperty(child, "idVendor")
+			info.USBProductID = hexIntProperty(child, "idProduct")
+			info.Description = info.Product
+			C.IOObjectRelease(C.io_object_t(child))
+			break
+		}
+	}
+
+	return info
+}
+
+func stringProperty(entry C.io_registry_entry_t, key string) string {
+	cKey := C.CString(key)
+	defer C.free(unsafe.Pointer(cKey))
+
+	cStr := C.cfStringProperty(entry, cKey)
+	if cStr == nil {
+		return ""
+	}
+	defer C.free(unsafe.Pointer(cStr))
+	return C.GoString(cStr)
+}
+
+func hexIntProperty(entry C.io_registry_entry_t, key string) string {
+	cKey := C.CString(key)
+	defer C.free(unsafe.Pointer(cKey))
+
+	var v C.int
+	if C.cfIntProperty(entry, cKey, &v) == 0 {
+		return ""
+	}
+	return fmt.Sprintf("%04x", int(v))
+}
@@ -0,0 +1,66 @@
+// +build linux
+
+package serial
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// listPorts scans /sys/class/tty for ttys that have a backing device
+// (as opposed to virtual ttys like ptys), then walks each one's device
+// symlink up to its USB parent, if any, to collect vendor/product
+// metadata.
+func listPorts() ([]PortInfo, error) {
+	entries, err := ioutil.ReadDir("/sys/class/tty")
+	if err != nil {
+		return nil, err
+	}
+
+	var ports []PortInfo
+	for _, e := range entries {
+		devicePath := filepath.Join("/sys/class/tty", e.Name(), "device")
+		if _, err := os.Readlink(filepath.Join(devicePath, "driver")); err != nil {
+			// No backing driver: a virtual tty rather than a real port.
+			continue
+		}
+
+		info := PortInfo{Name: "/dev/" + e.Name()}
+		if usb, ok := findUSBParent(devicePath); ok {
+			info.Manufacturer = readSysAttr(usb, "manufacturer")
+			info.Product = readSysAttr(usb, "product")
+			info.SerialNumber = readSysAttr(usb, "serial")
+			info.USBVendorID = readSysAttr(usb, "idVendor")
+			info.USBProductID = readSysAttr(usb, "idProduct")
+			info.Description = info.Product
+		}
+		ports = append(ports, info)
+	}
+	return ports, nil
+}
+
+// findUSBParent resolves devicePath (a symlink into /sys/devices/...)
+// and walks up its directory tree looking for the USB device node that
+// owns it, identified by the presence of an idVendor file.
+func findUSBParent(devicePath string) (string, bool) {
+	real, err := filepath.EvalSymlinks(devicePath)
+	if err != nil {
+		return "", false
+	}
+	for dir := real; len(dir) > 1; dir = filepath.Dir(dir) {
+		if _, err := os.Stat(filepath.Join(dir, "idVendor")); err == nil {
+			return dir, true
+		}
+	}
+	return "", false
+}
+
+func readSysAttr(dir, name string) string {
+	b, err := ioutil.ReadFile(filepath.Join(dir, name))
+	if err != nil {
+		return ""
+	}
+	return strings.TrimSpace(string(b))
+}
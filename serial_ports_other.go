@@ -0,0 +1,11 @@
+// +build !windows,!linux,!darwin
+
+package serial
+
+import "fmt"
+
+// listPorts is a stub for POSIX platforms this package does not yet
+// know how to enumerate serial ports on.
+func listPorts() ([]PortInfo, error) {
+	return nil, fmt.Errorf("serial: ListPorts is not implemented on this platform")
+}
@@ -0,0 +1,213 @@
+// +build windows
+
+package serial
+
+import (
+	"fmt"
+	"strings"
+	"syscall"
+	"unsafe"
+)
+
+var (
+	modSetupapi                           = syscall.NewLazyDLL("setupapi.dll")
+	procSetupDiGetClassDevsW              = modSetupapi.NewProc("SetupDiGetClassDevsW")
+	procSetupDiEnumDeviceInfo             = modSetupapi.NewProc("SetupDiEnumDeviceInfo")
+	procSetupDiGetDeviceRegistryPropertyW = modSetupapi.NewProc("SetupDiGetDeviceRegistryPropertyW")
+	procSetupDiDestroyDeviceInfoList      = modSetupapi.NewProc("SetupDiDestroyDeviceInfoList")
+
+	// advapi32 is loaded lazily for the same reason serial_windows.go
+	// lazily loads kernel32: RegEnumValueW is only needed by listPorts,
+	// so a missing export there shouldn't break anything else that
+	// imports this package.
+	modAdvapi32       = syscall.NewLazyDLL("advapi32.dll")
+	procRegEnumValueW = modAdvapi32.NewProc("RegEnumValueW")
+)
+
+// errorNoMoreItems is ERROR_NO_MORE_ITEMS from winerror.h. The syscall
+// package's registry support stops at RegEnumKeyEx (subkeys); it has
+// neither RegEnumValue (values) nor this constant, so both are
+// resolved directly against advapi32.dll below.
+const errorNoMoreItems = 259
+
+// regEnumValue wraps the advapi32 RegEnumValueW export that the
+// syscall package doesn't provide. Unlike most of the BOOL-returning
+// Win32 calls wrapped elsewhere in this package, RegEnumValueW's return
+// value is itself the Win32 error code (ERROR_SUCCESS on success), so
+// no separate GetLastError lookup is needed.
+func regEnumValue(key syscall.Handle, index uint32, nameBuf []uint16, nameLen *uint32, dataBuf []uint16, dataLen *uint32) error {
+	if err := procRegEnumValueW.Find(); err != nil {
+		return err
+	}
+	r, _, _ := procRegEnumValueW.Call(
+		uintptr(key), uintptr(index),
+		uintptr(unsafe.Pointer(&nameBuf[0])), uintptr(unsafe.Pointer(nameLen)),
+		0, 0,
+		uintptr(unsafe.Pointer(&dataBuf[0])), uintptr(unsafe.Pointer(dataLen)))
+	if r != 0 {
+		return syscall.Errno(r)
+	}
+	return nil
+}
+
+const (
+	digcfPresent     = 0x00000002
+	digcfAllClasses  = 0x00000004
+	sprdHardwareID   = 0x00000001
+	sprdFriendlyName = 0x0000000C
+	invalidHandle    = ^uintptr(0)
+)
+
+type spDevinfoData struct {
+	cbSize    uint32
+	classGUID syscall.GUID
+	devInst   uint32
+	reserved  uintptr
+}
+
+// listPorts reads the COM port names of the ports Windows currently has
+// installed out of HKLM\HARDWARE\DEVICEMAP\SERIALCOMM, then
+// joins that against Device Manager's view of the same ports (via
+// SetupDi*) to recover a description and USB VID/PID where available.
+func listPorts() ([]PortInfo, error) {
+	names, err := serialCommNames()
+	if err != nil {
+		return nil, err
+	}
+
+	details := devicePortDetails()
+
+	ports := make([]PortInfo, 0, len(names))
+	for _, name := range names {
+		info := PortInfo{Name: name}
+		if d, ok := details[name]; ok {
+			info.Description = d.friendlyName
+			info.USBVendorID = d.vendorID
+			info.USBProductID = d.productID
+		}
+		ports = append(ports, info)
+	}
+	return ports, nil
+}
+
+// serialCommNames enumerates the values of
+// HKLM\HARDWARE\DEVICEMAP\SERIALCOMM, whose value data is the COM port
+// name (e.g. "COM3") for every port currently installed.
+func serialCommNames() ([]string, error) {
+	var key syscall.Handle
+	err := syscall.RegOpenKeyEx(syscall.HKEY_LOCAL_MACHINE,
+		syscall.StringToUTF16Ptr(`HARDWARE\DEVICEMAP\SERIALCOMM`),
+		0, syscall.KEY_READ, &key)
+	if err == syscall.ERROR_FILE_NOT_FOUND {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("serial: opening SERIALCOMM registry key: %w", err)
+	}
+	defer syscall.RegCloseKey(key)
+
+	var names []string
+	for i := uint32(0); ; i++ {
+		nameBuf := make([]uint16, 256)
+		nameLen := uint32(len(nameBuf))
+		dataBuf := make([]uint16, 256)
+		dataLen := uint32(len(dataBuf) * 2)
+
+		err := regEnumValue(key, i, nameBuf, &nameLen, dataBuf, &dataLen)
+		if errno, ok := err.(syscall.Errno); ok && errno == errorNoMoreItems {
+			break
+		}
+		if err != nil {
+			return nil, fmt.Errorf("serial: enumerating SERIALCOMM registry key: %w", err)
+		}
+		names = append(names, syscall.UTF16ToString(dataBuf[:dataLen/2]))
+	}
+	return names, nil
+}
+
+type portDetails struct {
+	friendlyName string
+	vendorID     string
+	productID    string
+}
+
+// devicePortDetails walks every device in Device Manager and, for each
+// one whose friendly name ends in "(COMn)", records its hardware ID
+// (from which the USB VID/PID is parsed) keyed by that port name.
+func devicePortDetails() map[string]portDetails {
+	details := make(map[string]portDetails)
+
+	devs, _, _ := procSetupDiGetClassDevsW.Call(0, 0, 0, uintptr(digcfPresent|digcfAllClasses))
+	if devs == invalidHandle || devs == 0 {
+		return details
+	}
+	defer procSetupDiDestroyDeviceInfoList.Call(devs)
+
+	var data spDevinfoData
+	data.cbSize = uint32(unsafe.Sizeof(data))
+	for i := uint32(0); ; i++ {
+		r, _, _ := procSetupDiEnumDeviceInfo.Call(devs, uintptr(i), uintptr(unsafe.Pointer(&data)))
+		if r == 0 {
+			break
+		}
+
+		friendly := deviceRegistryStringProperty(devs, &data, sprdFriendlyName)
+		port := portNameFromFriendlyName(friendly)
+		if port == "" {
+			continue
+		}
+
+		hardwareID := deviceRegistryStringProperty(devs, &data, sprdHardwareID)
+		vid, pid := parseVIDPID(hardwareID)
+		details[port] = portDetails{friendlyName: friendly, vendorID: vid, productID: pid}
+	}
+	return details
+}
+
+// deviceRegistryStringProperty reads a REG_SZ device property, growing
+// the buffer until SetupDiGetDeviceRegistryPropertyW stops reporting
+// ERROR_INSUFFICIENT_BUFFER.
+func deviceRegistryStringProperty(devs uintptr, data *spDevinfoData, property uint32) string {
+	bufLen := uint32(256)
+	for {
+		buf := make([]uint16, bufLen/2)
+		var needed uint32
+		r, _, errno := procSetupDiGetDeviceRegistryPropertyW.Call(
+			devs, uintptr(unsafe.Pointer(data)), uintptr(property), 0,
+			uintptr(unsafe.Pointer(&buf[0])), uintptr(len(buf)*2), uintptr(unsafe.Pointer(&needed)))
+		if r != 0 {
+			return syscall.UTF16ToString(buf)
+		}
+		if errno == syscall.ERROR_INSUFFICIENT_BUFFER && needed > bufLen {
+			bufLen = needed
+			continue
+		}
+		return ""
+	}
+}
+
+// portNameFromFriendlyName extracts "COM3" out of a Device Manager
+// friendly name of the form "USB Serial Port (COM3)".
+func portNameFromFriendlyName(friendly string) string {
+	open := strings.LastIndex(friendly, "(COM")
+	if open < 0 {
+		return ""
+	}
+	closeIdx := strings.IndexByte(friendly[open:], ')')
+	if closeIdx < 0 {
+		return ""
+	}
+	return friendly[open+1 : open+closeIdx]
+}
+
+// parseVIDPID pulls the vendor/product IDs out of a hardware ID string
+// such as "USB\VID_0403&PID_6001&REV_0600".
+func parseVIDPID(hardwareID string) (vid, pid string) {
+	if i := strings.Index(hardwareID, "VID_"); i >= 0 && i+8 <= len(hardwareID) {
+		vid = hardwareID[i+4 : i+8]
+	}
+	if i := strings.Index(hardwareID, "PID_"); i >= 0 && i+8 <= len(hardwareID) {
+		pid = hardwareID[i+4 : i+8]
+	}
+	return vid, pid
+}
@@ -11,16 +11,32 @@ import "C"
 import (
 	"errors"
 	"fmt"
-	"io"
 	"os"
 	"syscall"
+	"time"
+	"unsafe"
+)
+
+// Modem control bits for the TIOCMGET/TIOCMBIS/TIOCMBIC ioctls, from
+// asm-generic/ioctls.h.
+const (
+	tiocmGet = 0x5415
+	tiocmBis = 0x5416
+	tiocmBic = 0x5417
+
+	tiocmDTR = 0x002
+	tiocmRTS = 0x004
+	tiocmCTS = 0x020
+	tiocmCAR = 0x040 // DCD
+	tiocmRNG = 0x080 // RI
+	tiocmDSR = 0x100
 )
 
 type serialPort struct {
 	f *os.File
 }
 
-func openPort(name string, baud int, spec []byte, flow []bool) (rwc io.ReadWriteCloser, err error) {
+func openPort(name string, baud int, spec []byte, flow []bool) (rwc Port, err error) {
 	port := new(serialPort)
 
 	f, err := os.OpenFile(name, syscall.O_RDWR|syscall.O_NOCTTY|syscall.O_NONBLOCK, 0666)
@@ -40,8 +56,15 @@ func openPort(name string, baud int, spec []byte, flow []bool) (rwc io.ReadWrite
 		f.Close()
 		return nil, err
 	}
+	// If baud matches one of the termios Bxxxxx constants, drive it
+	// through cfsetispeed/cfsetospeed as usual. Anything else is set
+	// below via setArbitraryBaud once the rest of the line discipline
+	// has been configured.
 	var speed C.speed_t
+	standardBaud := true
 	switch baud {
+	case 230400:
+		speed = C.B230400
 	case 115200:
 		speed = C.B115200
 	case 57600:
@@ -53,8 +76,8 @@ func openPort(name string, baud int, spec []byte, flow []bool) (rwc io.ReadWrite
 	case 9600:
 		speed = C.B9600
 	default:
-		f.Close()
-		return nil, fmt.Errorf("Unknown baud rate %v", baud)
+		standardBaud = false
+		speed = C.B38400 // placeholder; overridden by setArbitraryBaud below
 	}
 
 	_, err = C.cfsetispeed(&st, speed)
@@ -134,6 +157,13 @@ func openPort(name string, baud int, spec []byte, flow []bool) (rwc io.ReadWrite
 		return nil, err
 	}
 
+	if !standardBaud {
+		if err = setArbitraryBaud(f.Fd(), baud); err != nil {
+			f.Close()
+			return nil, fmt.Errorf("setting arbitrary baud rate %v: %v", baud, err)
+		}
+	}
+
 	//fmt.Println("Tweaking", name)
 	r1, _, e := syscall.Syscall(syscall.SYS_FCNTL,
 		uintptr(f.Fd()),
@@ -145,24 +175,108 @@ func openPort(name string, baud int, spec []byte, flow []bool) (rwc io.ReadWrite
 		return nil, errors.New(s)
 	}
 
-	/*
-				r1, _, e = syscall.Syscall(syscall.SYS_IOCTL,
-			                uintptr(f.Fd()),
-			                uintptr(0x80045402), // IOSSIOSPEED
-			                uintptr(unsafe.Pointer(&baud)));
-			        if e != 0 || r1 != 0 {
-			                s := fmt.Sprint("Baudrate syscall error:", e, r1)
-					f.Close()
-		                        return nil, os.NewError(s)
-				}
-	*/
-
 	port.f = f
 
 	return port, nil
 }
 
-func (p *serialPort) SetTimeouts(msec uint32) {
+// SetReadTimeout bounds Read() via the VMIN/VTIME termios fields: VMIN is
+// pinned at 0 so that a call never blocks past the timeout even when no
+// bytes have arrived, and VTIME is the timeout in deciseconds (the unit
+// termios uses), clamped to the single byte it is stored in. A zero
+// duration instead sets VMIN to 1 and VTIME to 0, so Read blocks until at
+// least one byte is available.
+func (p *serialPort) SetReadTimeout(d time.Duration) {
+	fd := C.int(p.f.Fd())
+
+	var st C.struct_termios
+	if _, err := C.tcgetattr(fd, &st); err != nil {
+		return
+	}
+
+	deciseconds := d / (100 * time.Millisecond)
+	if deciseconds > 255 {
+		deciseconds = 255
+	}
+	if deciseconds < 0 {
+		deciseconds = 0
+	}
+
+	if d == 0 {
+		st.c_cc[C.VMIN] = 1
+		st.c_cc[C.VTIME] = 0
+	} else {
+		st.c_cc[C.VMIN] = 0
+		st.c_cc[C.VTIME] = C.cc_t(deciseconds)
+	}
+
+	C.tcsetattr(fd, C.TCSANOW, &st)
+}
+
+// Flush waits for pending output to finish transmitting (tcdrain) and
+// then discards anything sitting in the input buffer (tcflush).
+func (p *serialPort) Flush() error {
+	fd := C.int(p.f.Fd())
+
+	if _, err := C.tcdrain(fd); err != nil {
+		return err
+	}
+	if _, err := C.tcflush(fd, C.TCIFLUSH); err != nil {
+		return err
+	}
+	return nil
+}
+
+// SendBreak transmits a break condition on the line. POSIX's
+// tcsendbreak does not take a portable duration (a non-zero argument is
+// implementation-defined, and glibc ignores it, sending a break of
+// roughly 0.25-0.4 seconds), so d is accepted for symmetry with the
+// Windows implementation but is otherwise advisory.
+func (p *serialPort) SendBreak(d time.Duration) error {
+	fd := C.int(p.f.Fd())
+
+	if _, err := C.tcsendbreak(fd, 0); err != nil {
+		return err
+	}
+	return nil
+}
+
+// setModemBit asserts or clears a single TIOCM_* bit via TIOCMBIS/TIOCMBIC.
+func (p *serialPort) setModemBit(bit uint32, on bool) error {
+	req := uintptr(tiocmBic)
+	if on {
+		req = tiocmBis
+	}
+	_, _, errno := syscall.Syscall(syscall.SYS_IOCTL, p.f.Fd(), req, uintptr(unsafe.Pointer(&bit)))
+	if errno != 0 {
+		return errno
+	}
+	return nil
+}
+
+// SetRTS asserts or clears the RTS line via TIOCMBIS/TIOCMBIC.
+func (p *serialPort) SetRTS(on bool) error {
+	return p.setModemBit(tiocmRTS, on)
+}
+
+// SetDTR asserts or clears the DTR line via TIOCMBIS/TIOCMBIC.
+func (p *serialPort) SetDTR(on bool) error {
+	return p.setModemBit(tiocmDTR, on)
+}
+
+// Status reports the modem status lines via TIOCMGET.
+func (p *serialPort) Status() (ModemStatus, error) {
+	var bits uint32
+	_, _, errno := syscall.Syscall(syscall.SYS_IOCTL, p.f.Fd(), tiocmGet, uintptr(unsafe.Pointer(&bits)))
+	if errno != 0 {
+		return ModemStatus{}, errno
+	}
+	return ModemStatus{
+		CTS: bits&tiocmCTS != 0,
+		DSR: bits&tiocmDSR != 0,
+		DCD: bits&tiocmCAR != 0,
+		RI:  bits&tiocmRNG != 0,
+	}, nil
 }
 
 func (p *serialPort) Read(buf []byte) (int, error) {
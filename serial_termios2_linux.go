@@ -0,0 +1,51 @@
+// +build linux
+
+package serial
+
+import (
+	"syscall"
+	"unsafe"
+)
+
+// termios2 mirrors struct termios2 from asm-generic/termbits.h. The
+// kernel accepts it via TCGETS2/TCSETS2 and, unlike the termios struct
+// cgo's <termios.h> exposes, lets c_ispeed/c_ospeed carry an arbitrary
+// integer baud rate when BOTHER is set in c_cflag.
+type termios2 struct {
+	c_iflag  uint32
+	c_oflag  uint32
+	c_cflag  uint32
+	c_lflag  uint32
+	c_line   byte
+	c_cc     [19]byte
+	c_ispeed uint32
+	c_ospeed uint32
+}
+
+// CBAUD/BOTHER from asm-generic/termbits.h: CBAUD is the mask of bits in
+// c_cflag that select the baud rate, and BOTHER is the special value
+// meaning "use c_ispeed/c_ospeed instead of a fixed Bxxxxx encoding".
+const (
+	cbaud  = 0o010017
+	bother = 0o010000
+)
+
+// setArbitraryBaud sets a baud rate that has no corresponding Bxxxxx
+// termios constant by going around cfsetispeed/cfsetospeed entirely and
+// issuing TCSETS2 with BOTHER and the raw rate in c_ispeed/c_ospeed.
+func setArbitraryBaud(fd uintptr, baud int) error {
+	var t termios2
+	if _, _, errno := syscall.Syscall(syscall.SYS_IOCTL, fd, uintptr(tcgets2), uintptr(unsafe.Pointer(&t))); errno != 0 {
+		return errno
+	}
+
+	t.c_cflag &^= cbaud
+	t.c_cflag |= bother
+	t.c_ispeed = uint32(baud)
+	t.c_ospeed = uint32(baud)
+
+	if _, _, errno := syscall.Syscall(syscall.SYS_IOCTL, fd, uintptr(tcsets2), uintptr(unsafe.Pointer(&t))); errno != 0 {
+		return errno
+	}
+	return nil
+}
@@ -4,11 +4,11 @@ package serial
 
 import (
 	"fmt"
-	"io"
 	"log"
 	"os"
 	"sync"
 	"syscall"
+	"time"
 	"unsafe"
 )
 
@@ -36,7 +36,7 @@ type serialPort struct {
 	rl sync.Mutex
 	wl sync.Mutex
 	st *structTimeouts
-	h syscall.Handle
+	h  syscall.Handle // event handle for the WaitCommEvent overlapped op
 	ro *syscall.Overlapped
 	wo *syscall.Overlapped
 }
@@ -58,7 +58,7 @@ type structTimeouts struct {
 	WriteTotalTimeoutConstant   uint32
 }
 
-func openPort(name string, baud int, spec []byte, flow []bool) (rwc io.ReadWriteCloser, err error) {
+func openPort(name string, baud int, spec []byte, flow []bool) (rwc Port, err error) {
 	if len(name) > 0 && name[0] != '\\' {
 		name = "\\\\.\\" + name
 	}
@@ -94,7 +94,7 @@ func openPort(name string, baud int, spec []byte, flow []bool) (rwc io.ReadWrite
 		log.Print("Failed to setupComm")
 		return
 	}
-	if err = setCommMask(h); err != nil {
+	if err = setCommMask(h, evRXChar); err != nil {
 		log.Print("Failed to setCommMask")
 		return
 	}
@@ -109,25 +109,43 @@ func openPort(name string, baud int, spec []byte, flow []bool) (rwc io.ReadWrite
 		log.Print("Failed to set wo with newOverlapped")
 		return
 	}
+	eh, err := createEvent()
+	if err != nil {
+		log.Print("Failed to create event for WaitForEvent")
+		return
+	}
 	port := new(serialPort)
 	port.f = f
 	port.fd = h
 	port.ro = ro
 	port.wo = wo
+	port.h = eh
 	var timeouts structTimeouts
 	port.st = &timeouts
-	port.SetTimeouts(100)
+	port.SetReadTimeout(100 * time.Millisecond)
 
 	return port, nil
 }
 
+// Close closes the underlying file handle along with the event handles
+// openPort created for it (the overlapped I/O events and, since
+// WaitForEvent was added, the WaitCommEvent event), none of which are
+// reclaimed by closing the file handle itself.
 func (p *serialPort) Close() error {
-	return p.f.Close()
+	err := p.f.Close()
+	for _, h := range []syscall.Handle{p.h, p.ro.HEvent, p.wo.HEvent} {
+		if e := syscall.CloseHandle(h); err == nil {
+			err = e
+		}
+	}
+	return err
 }
 
-func (p *serialPort) SetTimeouts(msec uint32){
+// SetReadTimeout bounds Read() by configuring the Windows comm timeouts.
+func (p *serialPort) SetReadTimeout(d time.Duration) {
+	msec := uint32(d / time.Millisecond)
 	timeouts := p.st
-	timeouts.ReadIntervalTimeout = msec/10
+	timeouts.ReadIntervalTimeout = msec / 10
 	timeouts.ReadTotalTimeoutMultiplier = msec
 	timeouts.ReadTotalTimeoutConstant = msec
 
@@ -153,8 +171,177 @@ func (p *serialPort) SetTimeouts(msec uint32){
 		       ReadTotalTimeoutConstant, ReadFile times out.
 	*/
 
-    p.st = timeouts
-    setCommTimeouts(p.h, timeouts)
+	p.st = timeouts
+	setCommTimeouts(p.fd, timeouts)
+}
+
+// drainPollInterval is how often Flush polls ClearCommError's
+// COMSTAT.cbOutQue while waiting for buffered output to drain.
+const drainPollInterval = 1 * time.Millisecond
+
+// Flush waits for buffered output to finish transmitting, the same
+// contract POSIX's tcdrain gives Flush on that platform, then discards
+// anything received but not yet read, via PurgeComm. PurgeComm's own
+// PURGE_TXCLEAR discards unsent output immediately rather than waiting
+// for it, so the wait is done separately first by polling cbOutQue.
+func (p *serialPort) Flush() error {
+	for {
+		stat, err := commStat(p.fd)
+		if err != nil {
+			return err
+		}
+		if stat.cbOutQue == 0 {
+			break
+		}
+		time.Sleep(drainPollInterval)
+	}
+	return purgeComm(p.fd, purgeTXClear|purgeRXClear)
+}
+
+// SendBreak transmits a break condition on the line for at least d.
+func (p *serialPort) SendBreak(d time.Duration) error {
+	if err := setCommBreak(p.fd); err != nil {
+		return err
+	}
+	time.Sleep(d)
+	return clearCommBreak(p.fd)
+}
+
+// Modem control function codes for EscapeCommFunction, from winbase.h.
+const (
+	escSETRTS = 3
+	escCLRRTS = 4
+	escSETDTR = 5
+	escCLRDTR = 6
+)
+
+// SetRTS asserts or clears RTS via EscapeCommFunction.
+func (p *serialPort) SetRTS(on bool) error {
+	f := uint32(escCLRRTS)
+	if on {
+		f = escSETRTS
+	}
+	return escapeCommFunction(p.fd, f)
+}
+
+// SetDTR asserts or clears DTR via EscapeCommFunction.
+func (p *serialPort) SetDTR(on bool) error {
+	f := uint32(escCLRDTR)
+	if on {
+		f = escSETDTR
+	}
+	return escapeCommFunction(p.fd, f)
+}
+
+// Modem status bits returned by GetCommModemStatus, from winbase.h.
+const (
+	msCTSOn  = 0x0010
+	msDSROn  = 0x0020
+	msRingOn = 0x0040
+	msRLSDOn = 0x0080
+)
+
+// Status reports the modem status lines via GetCommModemStatus.
+func (p *serialPort) Status() (ModemStatus, error) {
+	bits, err := getCommModemStatus(p.fd)
+	if err != nil {
+		return ModemStatus{}, err
+	}
+	return ModemStatus{
+		CTS: bits&msCTSOn != 0,
+		DSR: bits&msDSROn != 0,
+		DCD: bits&msRLSDOn != 0,
+		RI:  bits&msRingOn != 0,
+	}, nil
+}
+
+// Comm event bits for SetCommMask/WaitCommEvent, from winbase.h.
+const (
+	evRXChar = 0x0001
+	evCTS    = 0x0008
+	evDSR    = 0x0010
+	evRLSD   = 0x0020
+	evBreak  = 0x0040
+	evError  = 0x0080
+	evRing   = 0x0100
+)
+
+func toWinEventMask(mask EventMask) uint32 {
+	var w uint32
+	if mask&EventCTS != 0 {
+		w |= evCTS
+	}
+	if mask&EventDSR != 0 {
+		w |= evDSR
+	}
+	if mask&EventDCD != 0 {
+		w |= evRLSD
+	}
+	if mask&EventRing != 0 {
+		w |= evRing
+	}
+	if mask&EventBreak != 0 {
+		w |= evBreak
+	}
+	if mask&EventError != 0 {
+		w |= evError
+	}
+	return w
+}
+
+func fromWinEventMask(w uint32) EventMask {
+	var mask EventMask
+	if w&evCTS != 0 {
+		mask |= EventCTS
+	}
+	if w&evDSR != 0 {
+		mask |= EventDSR
+	}
+	if w&evRLSD != 0 {
+		mask |= EventDCD
+	}
+	if w&evRing != 0 {
+		mask |= EventRing
+	}
+	if w&evBreak != 0 {
+		mask |= EventBreak
+	}
+	if w&evError != 0 {
+		mask |= EventError
+	}
+	return mask
+}
+
+// WaitForEvent waits on the port's dedicated event handle for one of the
+// line events in mask, using WaitCommEvent with an overlapped operation
+// so that it does not block a concurrent Read/Write on the same handle.
+func (p *serialPort) WaitForEvent(mask EventMask) (EventMask, error) {
+	if err := setCommMask(p.fd, toWinEventMask(mask)); err != nil {
+		return 0, err
+	}
+	if err := resetEvent(p.h); err != nil {
+		return 0, err
+	}
+
+	overlapped := syscall.Overlapped{HEvent: p.h}
+	var winMask uint32
+	err := waitCommEvent(p.fd, &winMask, &overlapped)
+	if err != nil && err != syscall.ERROR_IO_PENDING {
+		return 0, err
+	}
+	if _, err := getOverlappedResult(p.fd, &overlapped); err != nil {
+		return 0, err
+	}
+
+	fired := fromWinEventMask(winMask)
+	if fired&EventError != 0 {
+		// The port won't report further events until its error flag
+		// is cleared.
+		if _, err := clearCommError(p.fd); err != nil {
+			return fired, err
+		}
+	}
+	return fired, nil
 }
 
 func (p *serialPort) Write(buf []byte) (int, error) {
@@ -191,38 +378,58 @@ func (p *serialPort) Read(buf []byte) (int, error) {
 	return getOverlappedResult(p.fd, p.ro)
 }
 
-var (
-	nSetCommState,
-	nSetCommTimeouts,
-	nSetCommMask,
-	nSetupComm,
-	nGetOverlappedResult,
-	nCreateEvent,
-	nResetEvent uintptr
+// PurgeComm flags, from winbase.h.
+const (
+	purgeTXAbort = 0x0001
+	purgeRXAbort = 0x0002
+	purgeTXClear = 0x0004
+	purgeRXClear = 0x0008
 )
 
-func init() {
-	k32, err := syscall.LoadLibrary("kernel32.dll")
-	if err != nil {
-		panic("LoadLibrary " + err.Error())
-	}
-	defer syscall.FreeLibrary(k32)
-
-	nSetCommState = getProcAddr(k32, "SetCommState")
-	nSetCommTimeouts = getProcAddr(k32, "SetCommTimeouts")
-	nSetCommMask = getProcAddr(k32, "SetCommMask")
-	nSetupComm = getProcAddr(k32, "SetupComm")
-	nGetOverlappedResult = getProcAddr(k32, "GetOverlappedResult")
-	nCreateEvent = getProcAddr(k32, "CreateEventW")
-	nResetEvent = getProcAddr(k32, "ResetEvent")
+// lazyProc is the subset of *syscall.LazyProc that the wrapper
+// functions below need. Tests substitute a fake implementation to
+// exercise them without calling into real kernel32 APIs.
+type lazyProc interface {
+	Find() error
+	Call(a ...uintptr) (r1, r2 uintptr, lastErr error)
 }
 
-func getProcAddr(lib syscall.Handle, name string) uintptr {
-	addr, err := syscall.GetProcAddress(lib, name)
-	if err != nil {
-		panic(name + " " + err.Error())
+// kernel32 is loaded lazily: the DLL is mapped and each proc address is
+// resolved the first time it's actually called, rather than eagerly at
+// package init, so a missing export only breaks the one operation that
+// needed it instead of the whole package failing to import.
+var kernel32 = syscall.NewLazyDLL("kernel32.dll")
+
+var (
+	procSetCommState        lazyProc = kernel32.NewProc("SetCommState")
+	procSetCommTimeouts     lazyProc = kernel32.NewProc("SetCommTimeouts")
+	procSetCommMask         lazyProc = kernel32.NewProc("SetCommMask")
+	procSetupComm           lazyProc = kernel32.NewProc("SetupComm")
+	procGetOverlappedResult lazyProc = kernel32.NewProc("GetOverlappedResult")
+	procCreateEventW        lazyProc = kernel32.NewProc("CreateEventW")
+	procResetEvent          lazyProc = kernel32.NewProc("ResetEvent")
+	procPurgeComm           lazyProc = kernel32.NewProc("PurgeComm")
+	procSetCommBreak        lazyProc = kernel32.NewProc("SetCommBreak")
+	procClearCommBreak      lazyProc = kernel32.NewProc("ClearCommBreak")
+	procEscapeCommFunction  lazyProc = kernel32.NewProc("EscapeCommFunction")
+	procGetCommModemStatus  lazyProc = kernel32.NewProc("GetCommModemStatus")
+	procWaitCommEvent       lazyProc = kernel32.NewProc("WaitCommEvent")
+	procClearCommError      lazyProc = kernel32.NewProc("ClearCommError")
+)
+
+// callProc resolves p, calling it only once the address is known to be
+// valid, and turns both a resolution failure and a zero (FALSE/NULL)
+// Win32 return value into a plain error instead of the panic that
+// *syscall.LazyProc.Call itself would raise for a missing export.
+func callProc(p lazyProc, a ...uintptr) (uintptr, error) {
+	if err := p.Find(); err != nil {
+		return 0, err
+	}
+	r, _, err := p.Call(a...)
+	if r == 0 {
+		return 0, err
 	}
-	return addr
+	return r, nil
 }
 
 func setCommState(h syscall.Handle, baud int, byteSize, stopBits, parity byte, flow []bool) error {
@@ -230,17 +437,17 @@ func setCommState(h syscall.Handle, baud int, byteSize, stopBits, parity byte, f
 	params.DCBlength = uint32(unsafe.Sizeof(params))
 
 	params.flags[0] = SERIAL_FLAGS_CLEAR
-	params.flags[0] |= 1  // fBinary (0b01)
+	params.flags[0] |= 1 // fBinary (0b01)
 
-        if flow[DTR_FLAG] {
+	if flow[DTR_FLAG] {
 		params.flags[FLAG_DTRCONTROL] |= DTR_CONTROL_ENABLE // Assert DSR
-        } else {
+	} else {
 		params.flags[FLAG_DTRCONTROL] |= DTR_CONTROL_HANDSHAKE // Assert DSR
 	}
 
-        if flow[RTS_FLAG] {
+	if flow[RTS_FLAG] {
 		params.flags[FLAG_RTSCONTROL] |= RTS_CONTROL_ENABLE // Assert RTS/CTS
-        } else {
+	} else {
 		params.flags[FLAG_RTSCONTROL] |= RTS_CONTROL_HANDSHAKE // Assert RTS/CTS
 	}
 
@@ -249,65 +456,125 @@ func setCommState(h syscall.Handle, baud int, byteSize, stopBits, parity byte, f
 	params.Parity = parity
 	params.StopBits = stopBits
 
-	r, _, err := syscall.Syscall(nSetCommState, 2, uintptr(h), uintptr(unsafe.Pointer(&params)), 0)
-	if r == 0 {
-		return err
-	}
-	return nil
+	_, err := callProc(procSetCommState, uintptr(h), uintptr(unsafe.Pointer(&params)))
+	return err
 }
 
 func setCommTimeouts(h syscall.Handle, timeouts *structTimeouts) error {
-	r, _, err := syscall.Syscall(nSetCommTimeouts, 2, uintptr(h), uintptr(unsafe.Pointer(timeouts)), 0)
-	if r == 0 {
-		return err
-	}
-	return nil
+	_, err := callProc(procSetCommTimeouts, uintptr(h), uintptr(unsafe.Pointer(timeouts)))
+	return err
 }
 
 func setupComm(h syscall.Handle, in, out int) error {
-	r, _, err := syscall.Syscall(nSetupComm, 3, uintptr(h), uintptr(in), uintptr(out))
-	if r == 0 {
-		return err
+	_, err := callProc(procSetupComm, uintptr(h), uintptr(in), uintptr(out))
+	return err
+}
+
+func setCommMask(h syscall.Handle, mask uint32) error {
+	_, err := callProc(procSetCommMask, uintptr(h), uintptr(mask))
+	return err
+}
+
+func escapeCommFunction(h syscall.Handle, function uint32) error {
+	_, err := callProc(procEscapeCommFunction, uintptr(h), uintptr(function))
+	return err
+}
+
+func getCommModemStatus(h syscall.Handle) (uint32, error) {
+	var bits uint32
+	_, err := callProc(procGetCommModemStatus, uintptr(h), uintptr(unsafe.Pointer(&bits)))
+	if err != nil {
+		return 0, err
 	}
-	return nil
+	return bits, nil
 }
 
-func setCommMask(h syscall.Handle) error {
-	const EV_RXCHAR = 1 /* 0b0001 */
-	r, _, err := syscall.Syscall(nSetCommMask, 2, uintptr(h), EV_RXCHAR, 0)
-	if r == 0 {
-		return err
+func waitCommEvent(h syscall.Handle, mask *uint32, overlapped *syscall.Overlapped) error {
+	_, err := callProc(procWaitCommEvent,
+		uintptr(h),
+		uintptr(unsafe.Pointer(mask)),
+		uintptr(unsafe.Pointer(overlapped)))
+	return err
+}
+
+// clearCommError clears the device's error flag (set by a framing,
+// overrun or parity error) so that further I/O is not blocked, and
+// returns the flags describing what went wrong.
+func clearCommError(h syscall.Handle) (uint32, error) {
+	var errs uint32
+	_, err := callProc(procClearCommError, uintptr(h), uintptr(unsafe.Pointer(&errs)), 0)
+	if err != nil {
+		return 0, err
 	}
-	return nil
+	return errs, nil
+}
+
+// comstat mirrors enough of winbase.h's COMSTAT for commStat's needs:
+// the packed status bits (fCtsHold etc.) are skipped since only the
+// queued byte counts are used.
+type comstat struct {
+	flags    uint32
+	cbInQue  uint32
+	cbOutQue uint32
+}
+
+// commStat reports the device's pending input/output byte counts via
+// ClearCommError, which also clears the device's error flag as a side
+// effect.
+func commStat(h syscall.Handle) (comstat, error) {
+	var errs uint32
+	var stat comstat
+	_, err := callProc(procClearCommError, uintptr(h), uintptr(unsafe.Pointer(&errs)), uintptr(unsafe.Pointer(&stat)))
+	if err != nil {
+		return comstat{}, err
+	}
+	return stat, nil
+}
+
+func purgeComm(h syscall.Handle, flags uint32) error {
+	_, err := callProc(procPurgeComm, uintptr(h), uintptr(flags))
+	return err
+}
+
+func setCommBreak(h syscall.Handle) error {
+	_, err := callProc(procSetCommBreak, uintptr(h))
+	return err
+}
+
+func clearCommBreak(h syscall.Handle) error {
+	_, err := callProc(procClearCommBreak, uintptr(h))
+	return err
 }
 
 func resetEvent(h syscall.Handle) error {
-	r, _, err := syscall.Syscall(nResetEvent, 1, uintptr(h), 0, 0)
-	if r == 0 {
-		return err
+	_, err := callProc(procResetEvent, uintptr(h))
+	return err
+}
+
+func createEvent() (syscall.Handle, error) {
+	r, err := callProc(procCreateEventW, 0, 1, 0, 0)
+	if err != nil {
+		return 0, err
 	}
-	return nil
+	return syscall.Handle(r), nil
 }
 
 func newOverlapped() (*syscall.Overlapped, error) {
-	var overlapped syscall.Overlapped
-	r, _, err := syscall.Syscall6(nCreateEvent, 4, 0, 1, 0, 0, 0, 0)
-	if r == 0 {
+	h, err := createEvent()
+	if err != nil {
 		return nil, err
 	}
-	overlapped.HEvent = syscall.Handle(r)
-	return &overlapped, nil
+	return &syscall.Overlapped{HEvent: h}, nil
 }
 
 func getOverlappedResult(h syscall.Handle, overlapped *syscall.Overlapped) (int, error) {
 	var n int
-	r, _, err := syscall.Syscall6(nGetOverlappedResult, 4,
+	_, err := callProc(procGetOverlappedResult,
 		uintptr(h),
 		uintptr(unsafe.Pointer(overlapped)),
-		uintptr(unsafe.Pointer(&n)), 1, 0, 0)
-	if r == 0 {
+		uintptr(unsafe.Pointer(&n)), 1)
+	if err != nil {
 		return n, err
 	}
-
 	return n, nil
 }
@@ -0,0 +1,79 @@
+// +build windows
+
+package serial
+
+import (
+	"errors"
+	"syscall"
+	"testing"
+)
+
+// fakeProc is a lazyProc that never touches a real DLL, so callProc can
+// be exercised without a live kernel32 export to resolve against.
+type fakeProc struct {
+	findErr error
+	r1      uintptr
+	callErr error
+	calls   [][]uintptr
+}
+
+func (f *fakeProc) Find() error { return f.findErr }
+
+func (f *fakeProc) Call(a ...uintptr) (uintptr, uintptr, error) {
+	f.calls = append(f.calls, a)
+	return f.r1, 0, f.callErr
+}
+
+func TestCallProcPropagatesFindError(t *testing.T) {
+	want := errors.New("proc not found")
+	p := &fakeProc{findErr: want}
+
+	_, err := callProc(p)
+	if err != want {
+		t.Fatalf("callProc() error = %v, want %v", err, want)
+	}
+}
+
+func TestCallProcPropagatesCallError(t *testing.T) {
+	want := syscall.Errno(5) // ERROR_ACCESS_DENIED
+	p := &fakeProc{r1: 0, callErr: want}
+
+	_, err := callProc(p, 1, 2, 3)
+	if err != want {
+		t.Fatalf("callProc() error = %v, want %v", err, want)
+	}
+}
+
+func TestCallProcReturnsValueOnSuccess(t *testing.T) {
+	p := &fakeProc{r1: 42}
+
+	r, err := callProc(p, 7)
+	if err != nil {
+		t.Fatalf("callProc() unexpected error: %v", err)
+	}
+	if r != 42 {
+		t.Fatalf("callProc() = %d, want 42", r)
+	}
+	if len(p.calls) != 1 || len(p.calls[0]) != 1 || p.calls[0][0] != 7 {
+		t.Fatalf("callProc() forwarded args = %v, want [[7]]", p.calls)
+	}
+}
+
+func TestSetCommMaskUsesCallProc(t *testing.T) {
+	orig := procSetCommMask
+	defer func() { procSetCommMask = orig }()
+
+	fake := &fakeProc{r1: 1}
+	procSetCommMask = fake
+
+	if err := setCommMask(syscall.Handle(3), evRXChar|evCTS); err != nil {
+		t.Fatalf("setCommMask() unexpected error: %v", err)
+	}
+	if len(fake.calls) != 1 {
+		t.Fatalf("setCommMask() called the proc %d times, want 1", len(fake.calls))
+	}
+	got := fake.calls[0]
+	if got[0] != 3 || got[1] != uintptr(evRXChar|evCTS) {
+		t.Fatalf("setCommMask() forwarded args = %v", got)
+	}
+}